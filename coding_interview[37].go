@@ -1,14 +1,21 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"math/rand"
+	"net/http"
 	"time"
 
 	"github.com/hibiken/asynq"
+	"github.com/nats-io/nats.go"
+	"github.com/redis/go-redis/v9"
 )
 
 // Instructions located at the bottom of this file.
@@ -20,6 +27,12 @@ type Associate struct {
 	ID    uint
 	Name  string
 	Email string
+
+	// Priority names the queue this associate's outreach is enqueued on
+	// (e.g. "vip", "default", "bulk"), which in turn determines how it's
+	// prioritized against other associates' work. See Blaster.queues.
+	// Associates with no priority set are treated as "default".
+	Priority string
 }
 
 // one of our prospective clients
@@ -47,6 +60,23 @@ type BlastUpdate struct {
 	ID             uint
 	BlastContactID uint
 	CreatedAt      time.Time
+
+	// TaskID is the deterministic idempotency key computed from the
+	// associate, contact, subject, and day (see computeTaskID). It lets
+	// Process recognize a retry of a task it already sent and reconciled
+	// (Sent == true) as a no-op. It does NOT make a send-then-crash window
+	// safe: if the process dies after mailer.Send succeeds but before Sent
+	// is flipped to true, a retry sees Sent == false and sends again. True
+	// once-only delivery would need the mailer itself to dedupe by TaskID
+	// (the remote mailers send asynq's task ID as task_id in their payload,
+	// and the HTTP one also as an X-Task-ID header, for exactly this
+	// reason), not just this row.
+	TaskID string
+	// Sent is false the moment this row is written, before the email is
+	// actually sent, and flipped to true once the send succeeds. A crash
+	// in between leaves an unreconciled row Process can detect — but not
+	// undo a duplicate send from, see TaskID above.
+	Sent bool
 }
 
 // -- Reference Interfaces --
@@ -70,11 +100,70 @@ type IRepo interface {
 	GetBlastUpdate(ctx context.Context, id uint) (*BlastUpdate, error)
 	ListBlastUpdates(ctx context.Context) ([]*BlastUpdate, error)
 	UpdateBlastUpdate(ctx context.Context, blastUpdate *BlastUpdate) error
+	// GetBlastUpdateByTaskID looks up a BlastUpdate by its idempotency key,
+	// or returns nil if no attempt has been recorded for that task yet.
+	GetBlastUpdateByTaskID(ctx context.Context, taskID string) (*BlastUpdate, error)
+
+	// WithTx runs fn with a context bound to a single database transaction,
+	// committing if fn returns nil and rolling back otherwise. Process runs
+	// the attempted BlastUpdate write and its later reconciliation as two
+	// separate WithTx calls, one write each.
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+
+	// LastBlastUpdateForAssociateContact returns the most recent BlastUpdate
+	// recording associateID having emailed contactID, or nil if they've
+	// never emailed that contact. Backs the "no repeat within 7 days" rule.
+	LastBlastUpdateForAssociateContact(ctx context.Context, associateID, contactID uint) (*BlastUpdate, error)
+	// AnyBlastUpdateForContactOnDate reports whether any associate emailed
+	// contactID on the given day, regardless of which associate. Backs the
+	// "no two associates email the same contact same-day" rule.
+	AnyBlastUpdateForContactOnDate(ctx context.Context, contactID uint, day time.Time) (bool, error)
+	// CountBlastUpdatesForAssociateOnDate counts how many emails
+	// associateID has sent on the given day. Backs the "max 100 per
+	// associate per day" rule.
+	CountBlastUpdatesForAssociateOnDate(ctx context.Context, associateID uint, day time.Time) (int, error)
 }
 
 // responsible for enqueuing tasks
 type IWorker interface {
-	Enqueue(ctx context.Context, task *asynq.Task) (*asynq.TaskInfo, error)
+	Enqueue(ctx context.Context, task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error)
+	// EnqueueAt schedules a task to become ready for processing at the given
+	// time, mirroring asynq.Client.EnqueueContext with a ProcessAt option.
+	EnqueueAt(ctx context.Context, at time.Time, task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error)
+	// EnqueueIn schedules a task to become ready for processing after d has
+	// elapsed, mirroring asynq.Client.EnqueueContext with a ProcessIn option.
+	EnqueueIn(ctx context.Context, d time.Duration, task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error)
+}
+
+// responsible for atomically reserving daily send slots so that concurrent
+// Queue calls (or multiple scheduler nodes running it) can't double-admit
+// the same associate/contact pair into the queue. Backed by Redis so the
+// reservation is shared across the whole fleet rather than held in a
+// single process' memory.
+//
+// Reserve is called from Queue only; Release is called from Queue when an
+// enqueue fails or conflicts, and from Process when a send fails on its
+// final attempt. Process does not reserve again, since by the time a task reaches Process the slot was already
+// taken at enqueue time, and re-running the same Lua script there would
+// just find its own lock still held and report no reservation available.
+// A task that reaches Process via a path other than Queue (a manual
+// enqueue, a redrive) skips this Redis-level check entirely, but
+// validateBlastContact still re-checks eligibility and the daily count
+// against the database before sending, so such a task can't bypass the
+// business rules themselves — only the Redis fast-path that keeps
+// obviously-ineligible contacts off the queue in the first place.
+type IQuota interface {
+	// Reserve atomically checks the associate's send count and the
+	// contact's lock for the given day, and if both are within bounds,
+	// increments the count and sets the lock. It reports whether the
+	// reservation was granted. day should be the calendar day the send is
+	// actually scheduled for — not necessarily today, since a task's
+	// FollowUpDate can be days out — so the lock is live on the day that
+	// matters rather than expiring hours before the send happens.
+	Reserve(ctx context.Context, associateID, contactID uint, day time.Time) (bool, error)
+	// Release undoes a reservation made by Reserve for the same day, for
+	// use when a task that reserved a slot ultimately fails to send.
+	Release(ctx context.Context, associateID, contactID uint, day time.Time) error
 }
 
 // -- TODO: Implement this interface --
@@ -117,17 +206,40 @@ type IBlaster interface {
 // Associate "george@company.com" can only email contact "jane@example.com" on or after 2024-01-02.
 // Associate "john@company.com" can only email contact "jane@example.com" again on or after 2024-01-08, and the subject could not be "Hello".
 
+// QueueConfig describes how a named queue should be scheduled and retried.
+// One entry per associate tier is typical, e.g. "vip", "default", "bulk".
+type QueueConfig struct {
+	// Weight is this queue's relative priority weight, fed straight into
+	// asynq.Config.Queues: asynq polls higher-weighted queues more often,
+	// so VIP outreach jumps ahead of bulk without starving it entirely.
+	// Note this is a *priority* knob, not a concurrency cap — asynq has no
+	// notion of a per-queue worker limit, only a process-wide one (see
+	// NewBlasterServer).
+	Weight     int
+	MaxTries   int
+	MaxRunTime time.Duration
+}
+
+// defaultQueueConfig is used for tasks on the "default" queue when queues
+// has no "default" entry of its own. Any associate whose Priority doesn't
+// match a configured queue lands on "default".
+var defaultQueueConfig = QueueConfig{Weight: 5, MaxTries: 5, MaxRunTime: 30 * time.Second}
+
 type Blaster struct {
 	mailer IMailer
 	repo   IRepo
 	worker IWorker
+	quota  IQuota
+	queues map[string]QueueConfig
 }
 
-func NewBlaster(repo IRepo, mailer IMailer, worker IWorker) *Blaster {
+func NewBlaster(repo IRepo, mailer IMailer, worker IWorker, quota IQuota, queues map[string]QueueConfig) *Blaster {
 	return &Blaster{
 		repo:   repo,
 		mailer: mailer,
 		worker: worker,
+		quota:  quota,
+		queues: queues,
 	}
 }
 
@@ -144,18 +256,38 @@ func (b *Blaster) Queue(ctx context.Context) error {
 		return fmt.Errorf("failed to list blast contacts: %w", err)
 	}
 
-	const maxEmailsPerDay = 100
-
 	for _, associate := range associates {
 		// Filter eligible blast contacts
-		eligibleContacts := b.filterEligibleContacts(blastContacts)
+		eligibleContacts, err := b.filterEligibleContacts(ctx, associate.ID, blastContacts)
+		if err != nil {
+			return fmt.Errorf("failed to filter eligible contacts for associate ID %d: %w", associate.ID, err)
+		}
 
 		count := 0
 		for _, contact := range eligibleContacts {
-			if count >= maxEmailsPerDay {
+			if count >= maxEmailsPerAssociatePerDay {
 				break
 			}
 
+			// day is the calendar day this send is actually scheduled for
+			// (today, or the contact's follow-up date if that's later) —
+			// computed before jitter so it's a stable anchor for both the
+			// quota reservation below and the task ID (see computeTaskID).
+			day := schedulingDay(contact.FollowUpDate)
+
+			// Reserve a send slot for this associate/contact pair, scoped
+			// to the day the send will actually happen, before enqueuing.
+			// This is the same invariant Process will re-check after
+			// dequeue, but reserving here keeps obviously ineligible
+			// contacts out of the queue entirely.
+			reserved, err := b.quota.Reserve(ctx, associate.ID, contact.ContactID, day)
+			if err != nil {
+				return fmt.Errorf("failed to reserve quota for blast contact ID %d: %w", contact.ID, err)
+			}
+			if !reserved {
+				continue
+			}
+
 			// Create task for the blast contact
 			data := map[string]interface{}{
 				"blast_contact_id":   contact.ID,
@@ -171,8 +303,41 @@ func (b *Blaster) Queue(ctx context.Context) error {
 			// Create the task with serialized payload
 			task := asynq.NewTask("process_email", payload)
 
-			_, err = b.worker.Enqueue(ctx, task)
-			if err != nil {
+			// Spread the actual send time across the day with jitter so
+			// we don't thunder-herd the SMTP provider at 8am. The jitter
+			// only affects when within `day` the send happens, not which
+			// day counts for quota/idempotency purposes.
+			// The jitter starts from now when `day` is today, so today's
+			// sends land after the Queue run rather than in the hours
+			// before it.
+			start := day
+			if now := time.Now(); now.After(start) {
+				start = now
+			}
+			at := start.Add(time.Duration(rand.Int63n(int64(maxEnqueueJitter))))
+
+			// A deterministic task ID makes re-running Queue (a retried
+			// cron fire, a manual re-run) a no-op instead of a duplicate
+			// send: asynq refuses a second task with the same ID.
+			taskID := computeTaskID(associate.ID, contact.ContactID, contact.Subject, day)
+			opts := append(b.queueOptionsForAssociate(associate, at), asynq.TaskID(taskID))
+
+			if _, err := b.worker.EnqueueAt(ctx, at, task, opts...); err != nil {
+				if errors.Is(err, asynq.ErrTaskIDConflict) {
+					// Already queued by an earlier run; the reservation
+					// we just took belongs to that earlier task, not a
+					// new one, so give it back.
+					if releaseErr := b.quota.Release(ctx, associate.ID, contact.ContactID, day); releaseErr != nil {
+						log.Printf("failed to release quota for blast contact ID %d: %v", contact.ID, releaseErr)
+					}
+					continue
+				}
+
+				// We already reserved the slot; give it back since the
+				// task never made it onto the queue.
+				if releaseErr := b.quota.Release(ctx, associate.ID, contact.ContactID, day); releaseErr != nil {
+					log.Printf("failed to release quota for blast contact ID %d: %v", contact.ID, releaseErr)
+				}
 				return fmt.Errorf("failed to enqueue task for blast contact ID %d: %w", contact.ID, err)
 			}
 
@@ -190,42 +355,737 @@ func (b *Blaster) Process(ctx context.Context, blastContact *BlastContact) error
 		return fmt.Errorf("blast contact validation failed: %w", err)
 	}
 
-	// Send email
-	if err := b.mailer.Send(ctx, blastContact); err != nil {
-		return fmt.Errorf("failed to send email: %w", err)
+	// Prefer the ID asynq assigned this task (set by Queue via
+	// asynq.TaskID) so retries of the same task share one idempotency key;
+	// fall back to recomputing it for calls that didn't come through the
+	// queue (e.g. direct/manual Process calls). The fallback uses the same
+	// schedulingDay Queue anchored the original ID to, not time.Now(), so a
+	// call on the day the task was scheduled for reproduces the same ID; a
+	// call on a later day for a FollowUpDate already past gets that later
+	// day's ID instead.
+	taskID, _ := asynq.GetTaskID(ctx)
+	if taskID == "" {
+		day := schedulingDay(blastContact.FollowUpDate)
+		taskID = computeTaskID(blastContact.AssociateID, blastContact.ContactID, blastContact.Subject, day)
+	}
+
+	existing, err := b.repo.GetBlastUpdateByTaskID(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to look up existing blast update: %w", err)
+	}
+	if existing != nil && existing.Sent {
+		// A prior attempt already sent and reconciled this exact task;
+		// treat a retry/duplicate delivery as a successful no-op.
+		return nil
 	}
 
-	// Record the blast update
 	blastUpdate := &BlastUpdate{
 		BlastContactID: blastContact.ID,
 		CreatedAt:      time.Now(),
+		TaskID:         taskID,
+		Sent:           false,
+	}
+	if existing != nil {
+		blastUpdate.ID = existing.ID
+		blastUpdate.CreatedAt = existing.CreatedAt
+	}
+
+	// Record the attempt before sending, inside its own transaction. This
+	// does not give us exactly-once delivery: if the process crashes after
+	// mailer.Send succeeds but before the reconcile write below runs, the
+	// row is left with Sent == false, and a retry reads that and sends
+	// again rather than detecting the prior success. What it does give us
+	// is an unreconciled row that's honest evidence a send may have
+	// happened and needs checking — strict once-only would need the mail
+	// send itself to participate in this transaction, which isn't possible
+	// across a network call to the mail provider, or the mailer to dedupe
+	// by TaskID on its own side (see BlastUpdate.TaskID).
+	if err := b.repo.WithTx(ctx, func(ctx context.Context) error {
+		return b.repo.UpdateBlastUpdate(ctx, blastUpdate)
+	}); err != nil {
+		return fmt.Errorf("failed to record blast attempt: %w", err)
+	}
+
+	// Send email
+	if err := b.mailer.Send(ctx, blastContact); err != nil {
+		// Only give back the quota slot reserved at Queue time once asynq
+		// has truly given up on this task — a transient error still has
+		// retries left, and the eventual successful attempt is the same
+		// reservation, not a new one. Releasing on every failure (including
+		// retryable ones) would let the associate's count go negative-net
+		// and reopen the contact's same-day lock while a retry is still
+		// pending, exactly the double-send/over-quota race Reserve exists
+		// to prevent. The attempted row is left unreconciled regardless; a
+		// retry will pick it up by TaskID above.
+		if isFinalAttempt(ctx, err) {
+			day := schedulingDay(blastContact.FollowUpDate)
+			if releaseErr := b.quota.Release(ctx, blastContact.AssociateID, blastContact.ContactID, day); releaseErr != nil {
+				log.Printf("failed to release quota for blast contact ID %d: %v", blastContact.ID, releaseErr)
+			}
+		}
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	// Reconcile: the email is sent, so mark the row as such.
+	blastUpdate.Sent = true
+	if err := b.repo.WithTx(ctx, func(ctx context.Context) error {
+		return b.repo.UpdateBlastUpdate(ctx, blastUpdate)
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile blast update: %w", err)
+	}
+
+	return nil
+}
+
+/***** SCHEDULING *****/
+
+// schedulingDay returns the ET calendar day (truncated to midnight) a send
+// is actually scheduled for: the later of "today" and followUpDate's date.
+// Both Queue and Process anchor their quota reservations and idempotency
+// keys to this value (computed before any jitter is applied) so they agree
+// on which day's invariants apply. Truncating to midnight — rather than
+// keeping followUpDate's time-of-day — matters because maxEnqueueJitter can
+// add up to 8h on top: if the untruncated time-of-day were late enough,
+// that jitter could push the actual send past the reservation's ET-midnight
+// TTL (see nextMidnightETAfter) into the following day, expiring the lock
+// before the send it's meant to cover.
+func schedulingDay(followUpDate time.Time) time.Time {
+	loc := etLocation()
+	now := time.Now().In(loc)
+	candidate := followUpDate.In(loc)
+	if candidate.Before(now) {
+		candidate = now
+	}
+	return time.Date(candidate.Year(), candidate.Month(), candidate.Day(), 0, 0, 0, 0, loc)
+}
+
+// isFinalAttempt reports whether asynq will not retry this task again after
+// the given send error — either because the error (or one it wraps) is
+// asynq.SkipRetry, or because this was already the last attempt asynq's
+// retry budget allows. Process uses this to decide whether a failed send
+// should give back its quota reservation: anything short of a final
+// attempt still has a pending retry that owns the same reservation.
+func isFinalAttempt(ctx context.Context, err error) bool {
+	if errors.Is(err, asynq.SkipRetry) {
+		return true
+	}
+	retryCount, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+	return retryCount >= maxRetry
+}
+
+// computeTaskID derives a deterministic asynq task ID from the fields that
+// define a unique blast for the day: associate, contact, subject, and date.
+// Passing this as asynq.TaskID(...) makes re-running Queue (a retried cron
+// fire, a manual re-run) a no-op instead of a duplicate send.
+func computeTaskID(associateID, contactID uint, subject string, day time.Time) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "%d|%d|%s|%s", associateID, contactID, subject, day.Format("20060102"))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// maxEnqueueJitter is the widest random offset added to a task's scheduled
+// send time, spreading what would otherwise be a single thundering herd of
+// sends across the day.
+const maxEnqueueJitter = 8 * time.Hour
+
+// queueNameForAssociate picks the named queue an associate's tasks land on.
+// It's keyed off associate metadata today; a natural extension point is to
+// also weigh contact scoring (e.g. bump a normally-"default" associate's
+// task onto "vip" for a high-value contact).
+func (b *Blaster) queueNameForAssociate(associate *Associate) string {
+	if _, ok := b.queues[associate.Priority]; ok {
+		return associate.Priority
+	}
+	return "default"
+}
+
+// queueOptionsForAssociate maps an associate's queue to the asynq options
+// that should accompany every task enqueued on their behalf: which named
+// queue it lands in, and how persistently and how long it's allowed to run
+// once it becomes ready at the given scheduled time. The deadline is
+// anchored to `at` rather than to enqueue time, since a task's FollowUpDate
+// (and therefore `at`) can be days out — anchoring to time.Now() would leave
+// it past its own deadline the moment it became ready.
+func (b *Blaster) queueOptionsForAssociate(associate *Associate, at time.Time) []asynq.Option {
+	name := b.queueNameForAssociate(associate)
+
+	cfg, ok := b.queues[name]
+	if !ok {
+		cfg = defaultQueueConfig
+	}
+
+	return []asynq.Option{
+		asynq.Queue(name),
+		asynq.Retry(cfg.MaxTries),
+		asynq.Timeout(cfg.MaxRunTime),
+		asynq.Deadline(at.Add(cfg.MaxRunTime)),
+	}
+}
+
+/***** SERVER *****/
+
+// NewBlasterServer builds the asynq.Server/ServeMux pair a worker process
+// should run: one queue per entry in queues (weighted so VIP outreach is
+// polled more often than bulk), with Process wired up as the handler for
+// "process_email" tasks. The worker pool itself (asynq.Config.Concurrency,
+// the one real concurrency knob asynq exposes) defaults to the sum of the
+// configured weights, since asynq has no way to cap concurrency per queue.
+func NewBlasterServer(redisOpt asynq.RedisConnOpt, queues map[string]QueueConfig, blaster *Blaster) (*asynq.Server, *asynq.ServeMux) {
+	weights := make(map[string]int, len(queues))
+	concurrency := 0
+	for name, cfg := range queues {
+		weights[name] = cfg.Weight
+		concurrency += cfg.Weight
+	}
+
+	server := asynq.NewServer(redisOpt, asynq.Config{Queues: weights, Concurrency: concurrency})
+
+	mux := asynq.NewServeMux()
+	mux.HandleFunc("process_email", blaster.handleProcessEmailTask)
+
+	return server, mux
+}
+
+// handleProcessEmailTask adapts an asynq.Task into a call to Process,
+// looking up the full BlastContact record referenced by the task payload.
+func (b *Blaster) handleProcessEmailTask(ctx context.Context, task *asynq.Task) error {
+	var data struct {
+		BlastContactID uint `json:"blast_contact_id"`
+	}
+	if err := json.Unmarshal(task.Payload(), &data); err != nil {
+		return fmt.Errorf("failed to unmarshal task payload: %w", err)
+	}
+
+	blastContact, err := b.repo.GetBlastContact(ctx, data.BlastContactID)
+	if err != nil {
+		return fmt.Errorf("failed to load blast contact ID %d: %w", data.BlastContactID, err)
+	}
+
+	return b.Process(ctx, blastContact)
+}
+
+/***** INSPECTOR *****/
+
+// AssociateBlastStats is one associate's slice of BlasterStats.
+type AssociateBlastStats struct {
+	Enqueued   int
+	Scheduled  int
+	InProgress int
+	Retry      int
+	// Archived counts tasks that exhausted their retries. asynq calls this
+	// state "archived" (it renamed "dead" some versions back); we keep the
+	// field name in step with asynq's own terminology.
+	Archived int
+}
+
+// BlasterStats summarizes queue state broken down per associate, so an
+// operator can answer "why didn't jane@example.com get emailed today"
+// without reaching for raw Redis commands.
+type BlasterStats struct {
+	ByAssociate map[uint]*AssociateBlastStats
+}
+
+// BlasterInspector wraps an asynq.Inspector on the same Redis connection the
+// worker fleet uses, translating asynq's per-queue, per-state task lists
+// into the associate-shaped view Blaster's callers actually think in.
+type BlasterInspector struct {
+	inspector *asynq.Inspector
+	queues    map[string]QueueConfig
+}
+
+func NewBlasterInspector(redisOpt asynq.RedisConnOpt, queues map[string]QueueConfig) *BlasterInspector {
+	return &BlasterInspector{
+		inspector: asynq.NewInspector(redisOpt),
+		queues:    queues,
+	}
+}
+
+// Stats returns per-associate counts of tasks in each processing state,
+// across every configured queue. The per-associate breakdown comes from
+// asynq's task payloads (associate ID isn't something asynq indexes on),
+// so unlike a plain aggregate count this has to walk every task in every
+// state — inspectorPageSize/listAllTasks below page through all of them
+// rather than silently stopping at asynq's default 30-per-call limit.
+func (i *BlasterInspector) Stats(ctx context.Context) (*BlasterStats, error) {
+	stats := &BlasterStats{ByAssociate: make(map[uint]*AssociateBlastStats)}
+
+	for queue := range i.queues {
+		pending, err := listAllTasks(func(opts ...asynq.ListOption) ([]*asynq.TaskInfo, error) {
+			return i.inspector.ListPendingTasks(queue, opts...)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pending tasks for queue %q: %w", queue, err)
+		}
+		for _, t := range pending {
+			i.statFor(stats, t.Payload()).Enqueued++
+		}
+
+		scheduled, err := listAllTasks(func(opts ...asynq.ListOption) ([]*asynq.TaskInfo, error) {
+			return i.inspector.ListScheduledTasks(queue, opts...)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list scheduled tasks for queue %q: %w", queue, err)
+		}
+		for _, t := range scheduled {
+			i.statFor(stats, t.Payload()).Scheduled++
+		}
+
+		active, err := listAllTasks(func(opts ...asynq.ListOption) ([]*asynq.TaskInfo, error) {
+			return i.inspector.ListActiveTasks(queue, opts...)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list active tasks for queue %q: %w", queue, err)
+		}
+		for _, t := range active {
+			i.statFor(stats, t.Payload()).InProgress++
+		}
+
+		retry, err := listAllTasks(func(opts ...asynq.ListOption) ([]*asynq.TaskInfo, error) {
+			return i.inspector.ListRetryTasks(queue, opts...)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list retry tasks for queue %q: %w", queue, err)
+		}
+		for _, t := range retry {
+			i.statFor(stats, t.Payload()).Retry++
+		}
+
+		archived, err := listAllTasks(func(opts ...asynq.ListOption) ([]*asynq.TaskInfo, error) {
+			return i.inspector.ListArchivedTasks(queue, opts...)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list archived tasks for queue %q: %w", queue, err)
+		}
+		for _, t := range archived {
+			i.statFor(stats, t.Payload()).Archived++
+		}
+	}
+
+	return stats, nil
+}
+
+// inspectorPageSize is the page size listAllTasks requests per call. asynq
+// defaults to 30 tasks per ListXTasks call; a queue with more than that in
+// any one state would otherwise be silently truncated.
+const inspectorPageSize = 100
+
+// listAllTasks pages through an asynq ListXTasks-shaped call (the list
+// function should close over the queue name, passing through opts) until a
+// page comes back short of inspectorPageSize, collecting every task rather
+// than just the first page.
+func listAllTasks(list func(opts ...asynq.ListOption) ([]*asynq.TaskInfo, error)) ([]*asynq.TaskInfo, error) {
+	var all []*asynq.TaskInfo
+	for page := 1; ; page++ {
+		batch, err := list(asynq.PageSize(inspectorPageSize), asynq.Page(page))
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, batch...)
+		if len(batch) < inspectorPageSize {
+			return all, nil
+		}
+	}
+}
+
+// statFor returns (creating if necessary) the AssociateBlastStats bucket a
+// task payload belongs to.
+func (i *BlasterInspector) statFor(stats *BlasterStats, payload []byte) *AssociateBlastStats {
+	associateID, _ := associateIDFromPayload(payload)
+
+	s, ok := stats.ByAssociate[associateID]
+	if !ok {
+		s = &AssociateBlastStats{}
+		stats.ByAssociate[associateID] = s
+	}
+	return s
+}
+
+// ListPendingByAssociate lists an associate's tasks currently ready for
+// processing, across every configured queue.
+func (i *BlasterInspector) ListPendingByAssociate(ctx context.Context, associateID uint) ([]*asynq.TaskInfo, error) {
+	var matches []*asynq.TaskInfo
+	for queue := range i.queues {
+		pending, err := listAllTasks(func(opts ...asynq.ListOption) ([]*asynq.TaskInfo, error) {
+			return i.inspector.ListPendingTasks(queue, opts...)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pending tasks for queue %q: %w", queue, err)
+		}
+		for _, t := range pending {
+			if id, ok := associateIDFromPayload(t.Payload()); ok && id == associateID {
+				matches = append(matches, t)
+			}
+		}
+	}
+	return matches, nil
+}
+
+// ListScheduled lists every task waiting for its scheduled send time,
+// across every configured queue.
+func (i *BlasterInspector) ListScheduled(ctx context.Context) ([]*asynq.TaskInfo, error) {
+	var all []*asynq.TaskInfo
+	for queue := range i.queues {
+		scheduled, err := listAllTasks(func(opts ...asynq.ListOption) ([]*asynq.TaskInfo, error) {
+			return i.inspector.ListScheduledTasks(queue, opts...)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list scheduled tasks for queue %q: %w", queue, err)
+		}
+		all = append(all, scheduled...)
+	}
+	return all, nil
+}
+
+// ListArchived lists every task that exhausted its retries, across every
+// configured queue. asynq calls this the "archived" state (formerly "dead").
+func (i *BlasterInspector) ListArchived(ctx context.Context) ([]*asynq.TaskInfo, error) {
+	var all []*asynq.TaskInfo
+	for queue := range i.queues {
+		archived, err := listAllTasks(func(opts ...asynq.ListOption) ([]*asynq.TaskInfo, error) {
+			return i.inspector.ListArchivedTasks(queue, opts...)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list archived tasks for queue %q: %w", queue, err)
+		}
+		all = append(all, archived...)
+	}
+	return all, nil
+}
+
+// Redrive moves a dead (or retrying/scheduled) task back into its queue's
+// pending list for immediate reprocessing.
+func (i *BlasterInspector) Redrive(ctx context.Context, queue, taskID string) error {
+	if err := i.inspector.RunTask(queue, taskID); err != nil {
+		return fmt.Errorf("failed to redrive task %q in queue %q: %w", taskID, queue, err)
+	}
+	return nil
+}
+
+// associateIDFromPayload pulls the associate ID out of a "process_email"
+// task's JSON payload without requiring the caller to know the full
+// BlastContact shape.
+func associateIDFromPayload(payload []byte) (uint, bool) {
+	var data struct {
+		BlastAssociateID uint `json:"blast_associate_id"`
+	}
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return 0, false
+	}
+	return data.BlastAssociateID, true
+}
+
+/***** QUOTA *****/
+
+const maxEmailsPerAssociatePerDay = 100
+
+// reserveScript atomically enforces the two quota invariants and reserves a
+// slot in a single round trip: KEYS[1] is the associate's daily counter,
+// KEYS[2] is the contact's daily lock. ARGV[1] is the max sends per
+// associate per day, ARGV[2] is the TTL (in seconds) until next midnight ET.
+// Returns 1 if the reservation was granted, 0 otherwise.
+var reserveScript = redis.NewScript(`
+local count = tonumber(redis.call("GET", KEYS[1]) or "0")
+if count >= tonumber(ARGV[1]) then
+	return 0
+end
+if redis.call("EXISTS", KEYS[2]) == 1 then
+	return 0
+end
+redis.call("INCR", KEYS[1])
+redis.call("EXPIRE", KEYS[1], ARGV[2])
+redis.call("SET", KEYS[2], "1", "EX", ARGV[2])
+return 1
+`)
+
+// releaseScript is the compensating action for reserveScript, undoing a
+// reservation when the task that made it ultimately fails.
+var releaseScript = redis.NewScript(`
+if tonumber(redis.call("GET", KEYS[1]) or "0") > 0 then
+	redis.call("DECR", KEYS[1])
+end
+redis.call("DEL", KEYS[2])
+return 1
+`)
+
+// redisQuota is the Redis-backed IQuota implementation. It piggybacks on the
+// same Redis instance asynq uses, so no additional infrastructure is needed.
+type redisQuota struct {
+	client *redis.Client
+}
+
+func NewRedisQuota(client *redis.Client) *redisQuota {
+	return &redisQuota{client: client}
+}
+
+func (q *redisQuota) Reserve(ctx context.Context, associateID, contactID uint, day time.Time) (bool, error) {
+	ttl := time.Until(nextMidnightETAfter(day))
+
+	res, err := reserveScript.Run(ctx, q.client,
+		[]string{associateCountKey(associateID, day), contactLockKey(contactID, day)},
+		maxEmailsPerAssociatePerDay, int(ttl.Seconds()),
+	).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to run quota reserve script: %w", err)
 	}
 
-	if err := b.repo.UpdateBlastUpdate(ctx, blastUpdate); err != nil {
-		return fmt.Errorf("failed to record blast update: %w", err)
+	return res == 1, nil
+}
+
+func (q *redisQuota) Release(ctx context.Context, associateID, contactID uint, day time.Time) error {
+	_, err := releaseScript.Run(ctx, q.client,
+		[]string{associateCountKey(associateID, day), contactLockKey(contactID, day)},
+	).Result()
+	if err != nil {
+		return fmt.Errorf("failed to run quota release script: %w", err)
 	}
 
 	return nil
 }
 
+func associateCountKey(associateID uint, day time.Time) string {
+	return fmt.Sprintf("associate:%d:%s:count", associateID, dayKeyET(day))
+}
+
+func contactLockKey(contactID uint, day time.Time) string {
+	return fmt.Sprintf("contact:%d:%s:locked", contactID, dayKeyET(day))
+}
+
+func dayKeyET(day time.Time) string {
+	return day.In(etLocation()).Format("20060102")
+}
+
+// nextMidnightETAfter returns ET midnight at the start of the day after
+// `day`'s calendar date, so a key reserved against a future scheduling day
+// doesn't expire before that day even arrives.
+func nextMidnightETAfter(day time.Time) time.Time {
+	loc := etLocation()
+	d := day.In(loc)
+	midnight := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+	return midnight.AddDate(0, 0, 1)
+}
+
+func etLocation() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+/***** REMOTE MAILER *****/
+
+// remoteMailPayload is what gets sent to an out-of-process mail handler:
+// the contact to email, plus enough of the asynq task's metadata for that
+// handler to make retry/timeout decisions consistent with Process's own.
+type remoteMailPayload struct {
+	BlastContact *BlastContact `json:"blast_contact"`
+	Deadline     *time.Time    `json:"deadline,omitempty"`
+	TaskID       string        `json:"task_id,omitempty"`
+	RetryCount   int           `json:"retry_count"`
+	MaxRetry     int           `json:"max_retry"`
+}
+
+// remoteMailResponse is the envelope every remote mailer implementation
+// (HTTP, NATS, ...) is expected to reply with.
+type remoteMailResponse struct {
+	Status string `json:"status"` // "ok", "retry", or "terminal"
+	Error  string `json:"error"`
+}
+
+func buildRemoteMailPayload(ctx context.Context, blastContact *BlastContact) remoteMailPayload {
+	taskID, _ := asynq.GetTaskID(ctx)
+	retryCount, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+	payload := remoteMailPayload{
+		BlastContact: blastContact,
+		TaskID:       taskID,
+		RetryCount:   retryCount,
+		MaxRetry:     maxRetry,
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		payload.Deadline = &deadline
+	}
+	return payload
+}
+
+// interpretRemoteMailResponse turns a remote mail handler's envelope into
+// the error shape asynq expects: nil to acknowledge, a plain error to
+// retry, or asynq.SkipRetry to give up without burning further attempts.
+func interpretRemoteMailResponse(resp remoteMailResponse) error {
+	switch resp.Status {
+	case "ok":
+		return nil
+	case "retry":
+		return fmt.Errorf("remote mailer requested retry: %s", resp.Error)
+	case "terminal":
+		return fmt.Errorf("remote mailer reported terminal failure: %s: %w", resp.Error, asynq.SkipRetry)
+	default:
+		return fmt.Errorf("remote mailer returned unrecognized status %q", resp.Status)
+	}
+}
+
+// IRemoteMailer is an IMailer that dispatches the actual send to an
+// out-of-process handler (a Python/Node microservice, say) instead of
+// sending directly, so Process can stay the orchestrator while delivery
+// lives elsewhere.
+type IRemoteMailer interface {
+	IMailer
+}
+
+// httpRemoteMailer POSTs the mail payload to a configured HTTP endpoint.
+type httpRemoteMailer struct {
+	endpoint string
+	client   *http.Client
+}
+
+func NewHTTPRemoteMailer(endpoint string, client *http.Client) *httpRemoteMailer {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &httpRemoteMailer{endpoint: endpoint, client: client}
+}
+
+func (m *httpRemoteMailer) Send(ctx context.Context, blastContact *BlastContact) error {
+	body, err := json.Marshal(buildRemoteMailPayload(ctx, blastContact))
+	if err != nil {
+		return fmt.Errorf("failed to marshal remote mail payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build remote mailer request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	taskID, _ := asynq.GetTaskID(ctx)
+	req.Header.Set("X-Task-ID", taskID)
+
+	httpResp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach remote mailer: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	// Only a 2xx body is expected to be the {status,...} envelope. A 5xx is
+	// the handler falling over (retryable), and a 4xx means we sent it
+	// something it will never accept (not worth retrying) — neither is
+	// guaranteed to come back as JSON, so check the status before decoding.
+	switch {
+	case httpResp.StatusCode >= 500:
+		return fmt.Errorf("remote mailer returned status %d", httpResp.StatusCode)
+	case httpResp.StatusCode >= 400:
+		return fmt.Errorf("remote mailer rejected request with status %d: %w", httpResp.StatusCode, asynq.SkipRetry)
+	}
+
+	var resp remoteMailResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return fmt.Errorf("failed to decode remote mailer response: %w", err)
+	}
+
+	return interpretRemoteMailResponse(resp)
+}
+
+// natsRemoteMailer dispatches the mail payload over a NATS request/reply,
+// for teams that route mail-sending work through NATS instead of HTTP.
+type natsRemoteMailer struct {
+	conn    *nats.Conn
+	subject string
+	timeout time.Duration
+}
+
+func NewNATSRemoteMailer(conn *nats.Conn, subject string, timeout time.Duration) *natsRemoteMailer {
+	return &natsRemoteMailer{conn: conn, subject: subject, timeout: timeout}
+}
+
+func (m *natsRemoteMailer) Send(ctx context.Context, blastContact *BlastContact) error {
+	body, err := json.Marshal(buildRemoteMailPayload(ctx, blastContact))
+	if err != nil {
+		return fmt.Errorf("failed to marshal remote mail payload: %w", err)
+	}
+
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline && m.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+	}
+
+	msg, err := m.conn.RequestWithContext(ctx, m.subject, body)
+	if err != nil {
+		return fmt.Errorf("failed to reach remote mailer over NATS: %w", err)
+	}
+
+	var resp remoteMailResponse
+	if err := json.Unmarshal(msg.Data, &resp); err != nil {
+		return fmt.Errorf("failed to decode remote mailer response: %w", err)
+	}
+
+	return interpretRemoteMailResponse(resp)
+}
+
 /***** UTILITIES *****/
 
-// filterEligibleContacts filters contacts based on rules
-func (b *Blaster) filterEligibleContacts(contacts []*BlastContact) []*BlastContact {
+const associateContactCooldown = 7 * 24 * time.Hour
+
+// filterEligibleContacts filters contacts down to those associateID is
+// actually allowed to email today, per the two hard business invariants:
+// no repeat to the same contact within 7 days, and no same-day overlap with
+// another associate emailing that contact. Both are checked against actual
+// BlastUpdate history rather than the BlastContact record itself, since
+// that record only describes intent (a follow-up date), not what's already
+// been sent.
+func (b *Blaster) filterEligibleContacts(ctx context.Context, associateID uint, contacts []*BlastContact) ([]*BlastContact, error) {
 	var eligibleContacts []*BlastContact
 	for _, contact := range contacts {
-		// Skip if the contact was emailed within 7 days
-		if time.Since(contact.FollowUpDate) < 7*24*time.Hour {
+		// A BlastContact belongs to exactly one associate; skip the ones
+		// that aren't this associate's to send, otherwise the same record
+		// gets considered (and enqueued) under whichever associate's turn
+		// in the outer loop happens to reach it first.
+		if contact.AssociateID != associateID {
+			continue
+		}
+
+		eligible, err := b.isEligibleToSend(ctx, associateID, contact.ContactID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check eligibility for contact ID %d: %w", contact.ContactID, err)
+		}
+		if !eligible {
 			continue
 		}
 
-		// Add contact to eligible list
 		eligibleContacts = append(eligibleContacts, contact)
 	}
-	return eligibleContacts
+	return eligibleContacts, nil
 }
 
-// validateBlastContact checks if the blast contact follows the rules
+// isEligibleToSend reports whether associateID may email contactID right
+// now, per the 7-day-per-associate-contact and same-day-cross-associate
+// invariants. It does not check the per-associate daily volume cap; that's
+// enforced separately since it depends on how many contacts are being
+// considered together, not just this one pair.
+func (b *Blaster) isEligibleToSend(ctx context.Context, associateID, contactID uint) (bool, error) {
+	lastUpdate, err := b.repo.LastBlastUpdateForAssociateContact(ctx, associateID, contactID)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up last blast update: %w", err)
+	}
+	if lastUpdate != nil && time.Since(lastUpdate.CreatedAt) < associateContactCooldown {
+		return false, nil
+	}
+
+	emailedToday, err := b.repo.AnyBlastUpdateForContactOnDate(ctx, contactID, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to check same-day blast updates: %w", err)
+	}
+	if emailedToday {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// validateBlastContact checks if the blast contact follows the rules. It's
+// called from Process, after dequeue, because a task scheduled hours or
+// days earlier may no longer be valid: another associate may have emailed
+// the contact in the meantime, or the associate may have hit their daily cap.
 func (b *Blaster) validateBlastContact(ctx context.Context, blastContact *BlastContact) error {
 	// Check if the associate exists
 	associate, err := b.repo.GetAssociate(ctx, blastContact.AssociateID)
@@ -239,5 +1099,21 @@ func (b *Blaster) validateBlastContact(ctx context.Context, blastContact *BlastC
 		return errors.New("invalid contact ID")
 	}
 
+	eligible, err := b.isEligibleToSend(ctx, blastContact.AssociateID, blastContact.ContactID)
+	if err != nil {
+		return fmt.Errorf("failed to check send eligibility: %w", err)
+	}
+	if !eligible {
+		return errors.New("associate is not eligible to email this contact today")
+	}
+
+	count, err := b.repo.CountBlastUpdatesForAssociateOnDate(ctx, blastContact.AssociateID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to count today's blast updates for associate: %w", err)
+	}
+	if count >= maxEmailsPerAssociatePerDay {
+		return errors.New("associate has reached their daily email limit")
+	}
+
 	return nil
 }